@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	ActivityActionListCreated        = "list_created"
+	ActivityActionListDeleted        = "list_deleted"
+	ActivityActionItemAdded          = "item_added"
+	ActivityActionItemUpdated        = "item_updated"
+	ActivityActionItemToggled        = "item_toggled"
+	ActivityActionItemUpdateConflict = "item_update_conflict"
+	ActivityActionItemToggleConflict = "item_toggle_conflict"
+	ActivityActionItemsBulkUpdated   = "items_bulk_updated"
+
+	activityResourceTypeShoppingList = "shopping_list"
+)
+
+type ActivityEntity struct {
+	ID           int64           `json:"id" db:"id"`
+	UserID       *int64          `json:"userId,omitempty" db:"user_id"`
+	ResourceType string          `json:"resourceType" db:"resource_type"`
+	ResourceID   int64           `json:"resourceId" db:"resource_id"`
+	Action       string          `json:"action" db:"action"`
+	Payload      json.RawMessage `json:"payload" db:"payload"`
+	CreatedAt    time.Time       `json:"createdAt" db:"created_at"`
+}
+
+// ActivityCursor paginates activity by (created_at, id), the same ordering
+// as idx_activity_resource, so pages don't shift as new rows are inserted.
+type ActivityCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+func (c ActivityCursor) encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeActivityCursor(encoded string) (*ActivityCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.URLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid activity cursor: %w", err)
+	}
+
+	var nanos, id int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil {
+		return nil, fmt.Errorf("invalid activity cursor: %w", err)
+	}
+
+	return &ActivityCursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
+}
+
+// ActivityRecorder is invoked by ShoppingListService after every mutating
+// call. Recording must never fail the primary operation, so callers should
+// log and swallow any error it returns.
+type ActivityRecorder interface {
+	Record(ctx context.Context, userID *int64, resourceID int64, action string, payload any) error
+	GetActivity(ctx context.Context, listID int64, cursor *ActivityCursor, limit int) ([]*ActivityEntity, *ActivityCursor, error)
+}
+
+type PostgresActivityRecorder struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresActivityRecorder(db *pgxpool.Pool) *PostgresActivityRecorder {
+	return &PostgresActivityRecorder{db: db}
+}
+
+const (
+	recordActivityQuery = `
+		INSERT INTO activity (user_id, resource_type, resource_id, action, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())`
+
+	getActivityQuery = `
+		SELECT id, user_id, resource_type, resource_id, action, payload, created_at
+		FROM activity
+		WHERE resource_type = $1 AND resource_id = $2
+		AND ($3::timestamptz IS NULL OR (created_at, id) < ($3, $4))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5`
+)
+
+func (r *PostgresActivityRecorder) Record(ctx context.Context, userID *int64, listID int64, action string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity payload: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, recordActivityQuery, userID, activityResourceTypeShoppingList, listID, action, body)
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresActivityRecorder) GetActivity(ctx context.Context, listID int64, cursor *ActivityCursor, limit int) ([]*ActivityEntity, *ActivityCursor, error) {
+	var cursorCreatedAt any
+	var cursorID int64
+	if cursor != nil {
+		cursorCreatedAt = cursor.CreatedAt
+		cursorID = cursor.ID
+	}
+
+	rows, err := r.db.Query(ctx, getActivityQuery, activityResourceTypeShoppingList, listID, cursorCreatedAt, cursorID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get activity: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*ActivityEntity
+	for rows.Next() {
+		var entry ActivityEntity
+		if err := rows.Scan(&entry.ID, &entry.UserID, &entry.ResourceType, &entry.ResourceID, &entry.Action, &entry.Payload, &entry.CreatedAt); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan activity row: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating activity: %w", err)
+	}
+
+	var nextCursor *ActivityCursor
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		nextCursor = &ActivityCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return entries, nextCursor, nil
+}
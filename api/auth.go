@@ -1,34 +1,82 @@
 package api
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"strings"
 )
 
 const apiKeyHeader = "X-Api-Key"
+const authorizationHeader = "Authorization"
+const bearerPrefix = "Bearer "
 
+type contextKey string
+
+const userIDContextKey contextKey = "userID"
+
+// AuthService authenticates requests via a per-user JWT, falling back to the
+// shared X-Api-Key for service-to-service calls when apiKey is non-empty.
 type AuthService struct {
-	apiKey string
+	apiKey     string
+	jwtService JWTService
 }
 
-func NewAuthService(apiKey string) AuthService {
-	return AuthService{apiKey: apiKey}
+func NewAuthService(apiKey string, jwtService JWTService) AuthService {
+	return AuthService{apiKey: apiKey, jwtService: jwtService}
 }
 
 func (authService AuthService) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		apiKey := r.Header.Get(apiKeyHeader)
-
-		if apiKey != authService.apiKey {
-			w.WriteHeader(http.StatusUnauthorized)
-			_, err := w.Write([]byte("UNAUTHORIZED"))
-			if err != nil {
-				log.Printf("%v", err)
-			}
-			
+		// Registering and logging in are how a caller obtains a JWT, so
+		// they need no credentials.
+		if r.URL.Path == "/auth/register" || r.URL.Path == "/auth/login" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if userID, ok := authService.verifyBearerToken(r); ok {
+			ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		if authService.apiKey != "" && r.Header.Get(apiKeyHeader) == authService.apiKey {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+		if _, err := w.Write([]byte("UNAUTHORIZED")); err != nil {
+			log.Printf("%v", err)
+		}
 	})
 }
+
+func (authService AuthService) verifyBearerToken(r *http.Request) (int64, bool) {
+	return authService.verifyBearerHeader(r.Header.Get(authorizationHeader))
+}
+
+// verifyBearerHeader verifies a raw "Authorization"-style header value,
+// shared by the HTTP path (AuthMiddleware) and the gRPC path
+// (NewAuthUnaryInterceptor, which reads the same value out of metadata
+// instead of an http.Header).
+func (authService AuthService) verifyBearerHeader(header string) (int64, bool) {
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return 0, false
+	}
+
+	userID, err := authService.jwtService.VerifyToken(strings.TrimPrefix(header, bearerPrefix))
+	if err != nil {
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// UserIDFromContext returns the authenticated user's ID populated by
+// AuthMiddleware, or false if the request was authenticated via API key.
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(int64)
+	return userID, ok
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryEntity struct {
+	ID        int64  `json:"id" db:"id"`
+	Name      string `json:"name" db:"name"`
+	Color     string `json:"color" db:"color"`
+	SortOrder int32  `json:"sortOrder" db:"sort_order"`
+}
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, name string, color string, sortOrder int32) (*CategoryEntity, error)
+	GetCategories(ctx context.Context) ([]*CategoryEntity, error)
+	UpdateCategory(ctx context.Context, categoryID int64, name string, color string, sortOrder int32) (*CategoryEntity, error)
+	DeleteCategory(ctx context.Context, categoryID int64) error
+}
+
+type PostgresCategoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresCategoryRepository(db *pgxpool.Pool) *PostgresCategoryRepository {
+	return &PostgresCategoryRepository{db: db}
+}
+
+const (
+	createCategoryQuery = `
+		INSERT INTO categories (name, color, sort_order)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, color, sort_order`
+
+	getCategoriesQuery = `
+		SELECT id, name, color, sort_order
+		FROM categories
+		ORDER BY sort_order, name`
+
+	updateCategoryQuery = `
+		UPDATE categories
+		SET name = $1, color = $2, sort_order = $3
+		WHERE id = $4
+		RETURNING id, name, color, sort_order`
+
+	deleteCategoryQuery = `
+		DELETE FROM categories WHERE id = $1`
+)
+
+func (r *PostgresCategoryRepository) CreateCategory(ctx context.Context, name string, color string, sortOrder int32) (*CategoryEntity, error) {
+	var category CategoryEntity
+	err := r.db.QueryRow(ctx, createCategoryQuery, name, color, sortOrder).Scan(
+		&category.ID, &category.Name, &category.Color, &category.SortOrder,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *PostgresCategoryRepository) GetCategories(ctx context.Context) ([]*CategoryEntity, error) {
+	rows, err := r.db.Query(ctx, getCategoriesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []*CategoryEntity
+	for rows.Next() {
+		var category CategoryEntity
+		if err := rows.Scan(&category.ID, &category.Name, &category.Color, &category.SortOrder); err != nil {
+			return nil, fmt.Errorf("failed to scan category row: %w", err)
+		}
+		categories = append(categories, &category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *PostgresCategoryRepository) UpdateCategory(ctx context.Context, categoryID int64, name string, color string, sortOrder int32) (*CategoryEntity, error) {
+	var category CategoryEntity
+	err := r.db.QueryRow(ctx, updateCategoryQuery, name, color, sortOrder, categoryID).Scan(
+		&category.ID, &category.Name, &category.Color, &category.SortOrder,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NotFoundError{ResourceType: "category", ResourceID: categoryID}
+		}
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *PostgresCategoryRepository) DeleteCategory(ctx context.Context, categoryID int64) error {
+	result, err := r.db.Exec(ctx, deleteCategoryQuery, categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return NotFoundError{ResourceType: "category", ResourceID: categoryID}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const apiKeyMetadataKey = "x-api-key"
+const authorizationMetadataKey = "authorization"
+
+// NewAuthUnaryInterceptor authenticates gRPC calls the same way
+// AuthService.AuthMiddleware authenticates REST calls: a per-user bearer
+// JWT in the "authorization" metadata key, falling back to the shared
+// x-api-key for service-to-service callers. A verified JWT populates the
+// same userID context key AuthMiddleware does, so GRPCServer can scope
+// calls with ownerUserID exactly like ShoppingListService does.
+func NewAuthUnaryInterceptor(authService AuthService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "UNAUTHORIZED")
+		}
+
+		if userID, ok := authService.verifyBearerHeader(firstMetadataValue(md, authorizationMetadataKey)); ok {
+			return handler(context.WithValue(ctx, userIDContextKey, userID), req)
+		}
+
+		values := md.Get(apiKeyMetadataKey)
+		if authService.apiKey != "" && len(values) > 0 && values[0] == authService.apiKey {
+			return handler(ctx, req)
+		}
+
+		return nil, status.Error(codes.Unauthenticated, "UNAUTHORIZED")
+	}
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
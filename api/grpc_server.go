@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "shoppinglist/pb"
+)
+
+// GRPCServer exposes ShoppingListEntityRepository over gRPC. It calls the
+// same repository as ShoppingListService, including ownership scoping: each
+// method reads ownerUserID(ctx), which NewAuthUnaryInterceptor populates
+// from the caller's bearer JWT the same way AuthMiddleware does for REST.
+// Unlike ShoppingListService, it does not record activity.
+type GRPCServer struct {
+	pb.UnimplementedShoppingListServiceServer
+
+	repo ShoppingListEntityRepository
+}
+
+func NewGRPCServer(repo ShoppingListEntityRepository) *GRPCServer {
+	return &GRPCServer{repo: repo}
+}
+
+func (s *GRPCServer) GetShoppingLists(ctx context.Context, req *pb.GetShoppingListsRequest) (*pb.GetShoppingListsResponse, error) {
+	entities, err := s.repo.GetShoppingLists(ctx, ownerUserID(ctx))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get shopping lists: %v", err)
+	}
+
+	lists := make([]*pb.ShoppingList, 0, len(entities))
+	for _, entity := range entities {
+		lists = append(lists, shoppingListToProto(entity))
+	}
+
+	return &pb.GetShoppingListsResponse{ShoppingLists: lists}, nil
+}
+
+func (s *GRPCServer) CreateShoppingList(ctx context.Context, req *pb.CreateShoppingListRequest) (*pb.ShoppingList, error) {
+	entity, err := s.repo.CreateShoppingListEntity(ctx, req.GetName(), ownerUserID(ctx))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create shopping list: %v", err)
+	}
+
+	return shoppingListToProto(entity), nil
+}
+
+func (s *GRPCServer) DeleteShoppingList(ctx context.Context, req *pb.DeleteShoppingListRequest) (*pb.DeleteShoppingListResponse, error) {
+	if err := s.repo.DeleteShoppingListEntity(ctx, req.GetListId(), ownerUserID(ctx)); err != nil {
+		return nil, grpcErrorFromErr(err, "shopping list")
+	}
+
+	return &pb.DeleteShoppingListResponse{}, nil
+}
+
+func (s *GRPCServer) AddGroceryItem(ctx context.Context, req *pb.AddGroceryItemRequest) (*pb.GroceryItem, error) {
+	entity, err := s.repo.CreateGroceryItemEntity(ctx, req.GetListId(), req.GetName(), req.GetQuantity(), nil, ownerUserID(ctx))
+	if err != nil {
+		return nil, grpcErrorFromErr(err, "shopping list")
+	}
+
+	return groceryItemToProto(entity), nil
+}
+
+func (s *GRPCServer) UpdateGroceryItem(ctx context.Context, req *pb.UpdateGroceryItemRequest) (*pb.UpdateGroceryItemResponse, error) {
+	entity, err := s.repo.UpdateGroceryItemEntity(ctx, req.GetItemId(), req.GetListId(), req.GetName(), req.GetQuantity(), nil, req.GetVersion(), ownerUserID(ctx))
+	if err != nil {
+		var lockErr OptimisticLockError
+		if errors.As(err, &lockErr) {
+			return &pb.UpdateGroceryItemResponse{Result: &pb.UpdateGroceryItemResponse_Conflict{Conflict: conflictToProto(lockErr)}}, nil
+		}
+
+		return nil, grpcErrorFromErr(err, "grocery item")
+	}
+
+	return &pb.UpdateGroceryItemResponse{Result: &pb.UpdateGroceryItemResponse_Item{Item: groceryItemToProto(entity)}}, nil
+}
+
+func (s *GRPCServer) ToggleGroceryItem(ctx context.Context, req *pb.ToggleGroceryItemRequest) (*pb.ToggleGroceryItemResponse, error) {
+	entity, err := s.repo.ToggleGroceryItemEntity(ctx, req.GetItemId(), req.GetListId(), req.GetVersion(), ownerUserID(ctx))
+	if err != nil {
+		var lockErr OptimisticLockError
+		if errors.As(err, &lockErr) {
+			return &pb.ToggleGroceryItemResponse{Result: &pb.ToggleGroceryItemResponse_Conflict{Conflict: conflictToProto(lockErr)}}, nil
+		}
+
+		return nil, grpcErrorFromErr(err, "grocery item")
+	}
+
+	return &pb.ToggleGroceryItemResponse{Result: &pb.ToggleGroceryItemResponse_Item{Item: groceryItemToProto(entity)}}, nil
+}
+
+func shoppingListToProto(entity *ShoppingListEntity) *pb.ShoppingList {
+	items := make([]*pb.GroceryItem, 0, len(entity.ShoppingItems))
+	for i := range entity.ShoppingItems {
+		items = append(items, groceryItemToProto(&entity.ShoppingItems[i]))
+	}
+
+	return &pb.ShoppingList{
+		Id:            entity.ID,
+		Name:          entity.Name,
+		Version:       entity.Version,
+		ShoppingItems: items,
+	}
+}
+
+func groceryItemToProto(entity *GroceryItemEntity) *pb.GroceryItem {
+	return &pb.GroceryItem{
+		Id:        entity.ID,
+		Name:      entity.Name,
+		Quantity:  entity.Quantity,
+		Completed: entity.Completed,
+		Version:   entity.Version,
+	}
+}
+
+func conflictToProto(err OptimisticLockError) *pb.OptimisticLockConflict {
+	return &pb.OptimisticLockConflict{
+		Error:          staleClientStateError,
+		Message:        staleClientStateErrorMessage,
+		CurrentVersion: err.CurrentVersion,
+	}
+}
+
+// grpcErrorFromErr maps repository errors to gRPC status codes the same way
+// the REST handlers map them to HTTP status codes.
+func grpcErrorFromErr(err error, resourceType string) error {
+	var notFoundErr NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return status.Errorf(codes.NotFound, "%v", notFoundErr)
+	}
+
+	return status.Errorf(codes.Internal, "%s: %v", resourceType, err)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "shoppinglist/pb"
+)
+
+// fakeShoppingListRepository records the ownerUserID it was called with, so
+// tests can assert that NewAuthUnaryInterceptor's userID made it all the way
+// into the repository call instead of being dropped as nil.
+type fakeShoppingListRepository struct {
+	ShoppingListEntityRepository
+	lastOwnerUserID *int64
+}
+
+func (f *fakeShoppingListRepository) GetShoppingLists(ctx context.Context, ownerUserID *int64) ([]*ShoppingListEntity, error) {
+	f.lastOwnerUserID = ownerUserID
+	return nil, nil
+}
+
+func dialGRPCServer(t *testing.T, repo ShoppingListEntityRepository, authService AuthService) (pb.ShoppingListServiceClient, func()) {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(NewAuthUnaryInterceptor(authService)))
+	pb.RegisterShoppingListServiceServer(grpcServer, NewGRPCServer(repo))
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial bufconn: %v", err)
+	}
+
+	return pb.NewShoppingListServiceClient(conn), func() {
+		conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+func TestGRPCServer_RejectsCallsWithoutCredentials(t *testing.T) {
+	jwtService := NewHMACJWTService([]byte("test-secret"), time.Hour)
+	authService := NewAuthService("", jwtService)
+	repo := &fakeShoppingListRepository{}
+	client, closeFn := dialGRPCServer(t, repo, authService)
+	defer closeFn()
+
+	_, err := client.GetShoppingLists(context.Background(), &pb.GetShoppingListsRequest{})
+	if err == nil {
+		t.Fatal("expected an unauthenticated error, got nil")
+	}
+}
+
+func TestGRPCServer_ScopesCallsToTheBearerJWTUser(t *testing.T) {
+	jwtService := NewHMACJWTService([]byte("test-secret"), time.Hour)
+	authService := NewAuthService("", jwtService)
+	repo := &fakeShoppingListRepository{}
+	client, closeFn := dialGRPCServer(t, repo, authService)
+	defer closeFn()
+
+	token, err := jwtService.IssueToken(42)
+	if err != nil {
+		t.Fatalf("issue token: %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", bearerPrefix+token)
+	if _, err := client.GetShoppingLists(ctx, &pb.GetShoppingListsRequest{}); err != nil {
+		t.Fatalf("GetShoppingLists: %v", err)
+	}
+
+	if repo.lastOwnerUserID == nil || *repo.lastOwnerUserID != 42 {
+		t.Fatalf("expected ownerUserID 42, got %v", repo.lastOwnerUserID)
+	}
+}
+
+func TestGRPCServer_AllowsTheSharedAPIKeyAsServiceToService(t *testing.T) {
+	jwtService := NewHMACJWTService([]byte("test-secret"), time.Hour)
+	authService := NewAuthService("shared-service-key", jwtService)
+	repo := &fakeShoppingListRepository{}
+	client, closeFn := dialGRPCServer(t, repo, authService)
+	defer closeFn()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadataKey, "shared-service-key")
+	if _, err := client.GetShoppingLists(ctx, &pb.GetShoppingListsRequest{}); err != nil {
+		t.Fatalf("GetShoppingLists: %v", err)
+	}
+
+	if repo.lastOwnerUserID != nil {
+		t.Fatalf("expected nil ownerUserID for API-key auth, got %v", *repo.lastOwnerUserID)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ParseRSAPrivateKeyPEM parses a PEM-encoded PKCS#1 or PKCS#8 RSA private
+// key, for callers configuring an RS256 JWTService from a key file.
+func ParseRSAPrivateKeyPEM(pemBytes []byte) (*rsa.PrivateKey, error) {
+	return jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+}
+
+// ParseRSAPublicKeyPEM parses a PEM-encoded PKIX RSA public key, for
+// callers configuring an RS256 JWTService from a key file.
+func ParseRSAPublicKeyPEM(pemBytes []byte) (*rsa.PublicKey, error) {
+	return jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+}
+
+const jwtIssuer = "shoppinglist"
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// JWTAlgorithm selects how JWTService signs and verifies tokens.
+type JWTAlgorithm string
+
+const (
+	JWTAlgorithmHS256 JWTAlgorithm = "HS256"
+	JWTAlgorithmRS256 JWTAlgorithm = "RS256"
+)
+
+type JWTService struct {
+	algorithm  JWTAlgorithm
+	hmacSecret []byte
+	rsaPrivate *rsa.PrivateKey
+	rsaPublic  *rsa.PublicKey
+	ttl        time.Duration
+}
+
+func NewHMACJWTService(secret []byte, ttl time.Duration) JWTService {
+	return JWTService{algorithm: JWTAlgorithmHS256, hmacSecret: secret, ttl: ttl}
+}
+
+func NewRSAJWTService(private *rsa.PrivateKey, public *rsa.PublicKey, ttl time.Duration) JWTService {
+	return JWTService{algorithm: JWTAlgorithmRS256, rsaPrivate: private, rsaPublic: public, ttl: ttl}
+}
+
+type userClaims struct {
+	jwt.RegisteredClaims
+	UserID int64 `json:"userId"`
+}
+
+func (s JWTService) IssueToken(userID int64) (string, error) {
+	now := time.Now()
+	claims := userClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+		},
+		UserID: userID,
+	}
+
+	switch s.algorithm {
+	case JWTAlgorithmRS256:
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		return token.SignedString(s.rsaPrivate)
+	default:
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString(s.hmacSecret)
+	}
+}
+
+func (s JWTService) VerifyToken(tokenString string) (int64, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &userClaims{}, func(token *jwt.Token) (interface{}, error) {
+		switch s.algorithm {
+		case JWTAlgorithmRS256:
+			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return s.rsaPublic, nil
+		default:
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return s.hmacSecret, nil
+		}
+	})
+	if err != nil || !token.Valid {
+		return 0, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*userClaims)
+	if !ok {
+		return 0, ErrInvalidToken
+	}
+
+	return claims.UserID, nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTService_HMACRoundTrip(t *testing.T) {
+	service := NewHMACJWTService([]byte("test-secret"), time.Hour)
+
+	token, err := service.IssueToken(42)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	userID, err := service.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if userID != 42 {
+		t.Fatalf("expected userID 42, got %d", userID)
+	}
+}
+
+func TestJWTService_RSARoundTrip(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+
+	service := NewRSAJWTService(privateKey, &privateKey.PublicKey, time.Hour)
+
+	token, err := service.IssueToken(7)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	userID, err := service.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if userID != 7 {
+		t.Fatalf("expected userID 7, got %d", userID)
+	}
+}
+
+func TestJWTService_RejectsWrongHMACSecret(t *testing.T) {
+	issuer := NewHMACJWTService([]byte("secret-a"), time.Hour)
+	verifier := NewHMACJWTService([]byte("secret-b"), time.Hour)
+
+	token, err := issuer.IssueToken(1)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	if _, err := verifier.VerifyToken(token); err == nil {
+		t.Fatal("expected verification to fail with a different HMAC secret")
+	}
+}
+
+func TestJWTService_RejectsAlgorithmConfusion(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+
+	// Sign an HS256 token using the RSA public key's modulus as the HMAC
+	// secret, the classic RS256-to-HS256 confusion attack: a client with
+	// only the public key shouldn't be able to forge a token the server
+	// accepts.
+	claims := userClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		UserID: 99,
+	}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(privateKey.PublicKey.N.Bytes())
+	if err != nil {
+		t.Fatalf("sign forged token: %v", err)
+	}
+
+	service := NewRSAJWTService(privateKey, &privateKey.PublicKey, time.Hour)
+	if _, err := service.VerifyToken(forged); err == nil {
+		t.Fatal("expected an RS256 service to reject an HS256-signed token")
+	}
+}
+
+func TestJWTService_RejectsExpiredToken(t *testing.T) {
+	service := NewHMACJWTService([]byte("test-secret"), -time.Hour)
+
+	token, err := service.IssueToken(1)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	if _, err := service.VerifyToken(token); err == nil {
+		t.Fatal("expected an already-expired token to fail verification")
+	}
+}
@@ -18,17 +18,20 @@ type ShoppingListEntity struct {
 	Name          string              `json:"name" db:"name"`
 	CreatedAt     time.Time           `json:"createdAt" db:"created_at"`
 	Version       int64               `json:"version" db:"version"`
+	OwnerUserID   *int64              `json:"-" db:"owner_user_id"`
 	ShoppingItems []GroceryItemEntity `json:"shoppingItems,omitempty"`
 }
 
 type GroceryItemEntity struct {
-	ID        int64     `json:"id" db:"id"`
-	ListID    int64     `json:"listId" db:"list_id"`
-	Name      string    `json:"name" db:"name"`
-	Quantity  int32     `json:"quantity" db:"quantity"`
-	Completed bool      `json:"completed" db:"completed"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
-	Version   int64     `json:"version" db:"version"`
+	ID         int64           `json:"id" db:"id"`
+	ListID     int64           `json:"listId" db:"list_id"`
+	Name       string          `json:"name" db:"name"`
+	Quantity   int32           `json:"quantity" db:"quantity"`
+	Completed  bool            `json:"completed" db:"completed"`
+	CreatedAt  time.Time       `json:"createdAt" db:"created_at"`
+	Version    int64           `json:"version" db:"version"`
+	CategoryID *int64          `json:"-" db:"category_id"`
+	Category   *CategoryEntity `json:"category,omitempty" db:"-"`
 }
 
 type OptimisticLockError struct {
@@ -52,13 +55,59 @@ func (e NotFoundError) Error() string {
 	return fmt.Sprintf("%s with id %d not found", e.ResourceType, e.ResourceID)
 }
 
+// BulkItemOpKind identifies one operation within a batched items:bulk
+// request.
+type BulkItemOpKind string
+
+const (
+	BulkItemOpAdd    BulkItemOpKind = "add"
+	BulkItemOpUpdate BulkItemOpKind = "update"
+	BulkItemOpToggle BulkItemOpKind = "toggle"
+	BulkItemOpDelete BulkItemOpKind = "delete"
+)
+
+// BulkItemOp is one entry in a batched items:bulk request. ItemID is unused
+// for Add. Version is required for Update, Toggle, and Delete so they're
+// optimistically locked the same way their single-item counterparts are.
+type BulkItemOp struct {
+	Op         BulkItemOpKind
+	ItemID     int64
+	Name       string
+	Quantity   int32
+	CategoryID *int64
+	Version    int64
+}
+
+// BulkItemOpResult reports the outcome of a single BulkItemOp. When Success
+// is true, Item holds the resulting item (nil for Delete); when Success is
+// false, exactly one of LockErr or Err is set.
+type BulkItemOpResult struct {
+	ItemID  int64
+	Success bool
+	Item    *GroceryItemEntity
+	LockErr *OptimisticLockError
+	Err     error
+}
+
+// errBulkOpFailed is returned from the withTx closure in BulkUpdateItems'
+// all-or-nothing mode purely to trigger a rollback; it never escapes to the
+// caller, who gets their answer from the per-op results slice instead.
+var errBulkOpFailed = errors.New("one or more bulk operations failed")
+
+// ownerUserID scopes a call to a single caller's lists when non-nil. It is
+// nil for the service-to-service API-key path, which is not scoped to a
+// single owner.
 type ShoppingListEntityRepository interface {
-	CreateShoppingListEntity(ctx context.Context, name string) (*ShoppingListEntity, error)
-	GetShoppingLists(ctx context.Context) ([]*ShoppingListEntity, error)
-	DeleteShoppingListEntity(ctx context.Context, listID int64) error
-	CreateGroceryItemEntity(ctx context.Context, listID int64, name string, quantity int32) (*GroceryItemEntity, error)
-	UpdateGroceryItemEntity(ctx context.Context, itemID int64, listID int64, name string, quantity int32, version int64) (*GroceryItemEntity, error)
-	ToggleGroceryItemEntity(ctx context.Context, itemID int64, listID int64, version int64) (*GroceryItemEntity, error)
+	CreateShoppingListEntity(ctx context.Context, name string, ownerUserID *int64) (*ShoppingListEntity, error)
+	GetShoppingLists(ctx context.Context, ownerUserID *int64) ([]*ShoppingListEntity, error)
+	DeleteShoppingListEntity(ctx context.Context, listID int64, ownerUserID *int64) error
+	GetShoppingListEntityByID(ctx context.Context, listID int64, ownerUserID *int64) (*ShoppingListEntity, error)
+	CreateGroceryItemEntity(ctx context.Context, listID int64, name string, quantity int32, categoryID *int64, ownerUserID *int64) (*GroceryItemEntity, error)
+	GetGroceryItemEntity(ctx context.Context, itemID int64, listID int64) (*GroceryItemEntity, error)
+	UpdateGroceryItemEntity(ctx context.Context, itemID int64, listID int64, name string, quantity int32, categoryID *int64, version int64, ownerUserID *int64) (*GroceryItemEntity, error)
+	ToggleGroceryItemEntity(ctx context.Context, itemID int64, listID int64, version int64, ownerUserID *int64) (*GroceryItemEntity, error)
+	GetShoppingListSummary(ctx context.Context, listID int64, ownerUserID *int64) (*ShoppingListSummary, error)
+	BulkUpdateItems(ctx context.Context, listID int64, ops []BulkItemOp, bestEffort bool, ownerUserID *int64) ([]BulkItemOpResult, error)
 }
 
 type ShoppingListRepository struct {
@@ -71,54 +120,78 @@ func NewShoppingListRepository(db *pgxpool.Pool) *ShoppingListRepository {
 
 const (
 	createShoppingListEntityQuery = `
-		INSERT INTO shopping_lists (name, created_at, version)
-		VALUES ($1, NOW(), 1)
+		INSERT INTO shopping_lists (name, created_at, version, owner_user_id)
+		VALUES ($1, NOW(), 1, $2)
 		RETURNING id, name, created_at, version`
 	getShoppingListsQuery = `
-		SELECT 
+		SELECT
 			sl.id, sl.name, sl.created_at, sl.version,
-			gi.id, gi.name, gi.quantity, gi.completed, gi.created_at, gi.version
+			gi.id, gi.name, gi.quantity, gi.completed, gi.created_at, gi.version,
+			c.id, c.name, c.color, c.sort_order
 		FROM shopping_lists sl
-		LEFT JOIN grocery_items gi ON sl.id = gi.list_id`
+		LEFT JOIN grocery_items gi ON sl.id = gi.list_id
+		LEFT JOIN categories c ON gi.category_id = c.id
+		WHERE $1::bigint IS NULL OR sl.owner_user_id = $1`
 
 	getShoppingListEntityByIDQuery = `
 		SELECT id, name, created_at, version
 		FROM shopping_lists
-		WHERE id = $1`
+		WHERE id = $1 AND ($2::bigint IS NULL OR owner_user_id = $2)`
 
 	deleteShoppingListEntityQuery = `
-		DELETE FROM shopping_lists where id = $1`
+		DELETE FROM shopping_lists WHERE id = $1 AND ($2::bigint IS NULL OR owner_user_id = $2)`
 	deleteGroceryItemEntitiesByListQuery = `
 		DELETE FROM grocery_items where list_id = $1`
 
 	createGroceryItemEntityQuery = `
-		INSERT INTO grocery_items (list_id, name, quantity, completed, created_at, version)
-		VALUES ($1, $2, $3, false, NOW(), 1)
-		RETURNING id, list_id, name, quantity, completed, created_at, version`
+		INSERT INTO grocery_items (list_id, name, quantity, completed, created_at, version, category_id)
+		VALUES ($1, $2, $3, false, NOW(), 1, $4)
+		RETURNING id, list_id, name, quantity, completed, created_at, version, category_id`
 
 	updateGroceryItemEntityQuery = `
 		UPDATE grocery_items
-		SET name = $1, quantity = $2, version = version + 1
-		WHERE id = $3 AND list_id = $4 AND version = $5
-		AND EXISTS (SELECT 1 FROM shopping_lists WHERE id = $4)
-		RETURNING id, list_id, name, quantity, completed, created_at, version`
+		SET name = $1, quantity = $2, category_id = $3, version = version + 1
+		WHERE id = $4 AND list_id = $5 AND version = $6
+		AND EXISTS (SELECT 1 FROM shopping_lists WHERE id = $5 AND ($7::bigint IS NULL OR owner_user_id = $7))
+		RETURNING id, list_id, name, quantity, completed, created_at, version, category_id`
 
 	toggleGroceryItemEntityQuery = `
 		UPDATE grocery_items
 		SET completed = NOT completed, version = version + 1
 		WHERE id = $1 AND list_id = $2 AND version = $3
-		AND EXISTS (SELECT 1 FROM shopping_lists WHERE id = $2)
+		AND EXISTS (SELECT 1 FROM shopping_lists WHERE id = $2 AND ($4::bigint IS NULL OR owner_user_id = $4))
 		RETURNING id, list_id, name, quantity, completed, created_at, version`
 
+	bulkDeleteGroceryItemEntityQuery = `
+		DELETE FROM grocery_items
+		WHERE id = $1 AND list_id = $2 AND version = $3
+		AND EXISTS (SELECT 1 FROM shopping_lists WHERE id = $2 AND ($4::bigint IS NULL OR owner_user_id = $4))`
+
 	getCurrentGroceryItemEntityVersionQuery = `
-		SELECT version 
+		SELECT version
 		FROM grocery_items
 		WHERE id = $1 AND list_id = $2`
+
+	getGroceryItemEntityQuery = `
+		SELECT id, list_id, name, quantity, completed, created_at, version, category_id
+		FROM grocery_items
+		WHERE id = $1 AND list_id = $2`
+
+	getShoppingListSummaryQuery = `
+		SELECT
+			c.id, c.name, c.color, c.sort_order,
+			COUNT(gi.id), COUNT(gi.id) FILTER (WHERE gi.completed), COUNT(gi.id) FILTER (WHERE NOT gi.completed)
+		FROM grocery_items gi
+		LEFT JOIN categories c ON gi.category_id = c.id
+		WHERE gi.list_id = $1
+		AND EXISTS (SELECT 1 FROM shopping_lists WHERE id = $1 AND ($2::bigint IS NULL OR owner_user_id = $2))
+		GROUP BY c.id, c.name, c.color, c.sort_order
+		ORDER BY c.sort_order NULLS LAST, c.name`
 )
 
-func (r *ShoppingListRepository) CreateShoppingListEntity(ctx context.Context, name string) (*ShoppingListEntity, error) {
+func (r *ShoppingListRepository) CreateShoppingListEntity(ctx context.Context, name string, ownerUserID *int64) (*ShoppingListEntity, error) {
 	var list ShoppingListEntity
-	err := r.db.QueryRow(ctx, createShoppingListEntityQuery, name).Scan(
+	err := r.db.QueryRow(ctx, createShoppingListEntityQuery, name, ownerUserID).Scan(
 		&list.ID, &list.Name, &list.CreatedAt, &list.Version,
 	)
 	if err != nil {
@@ -127,8 +200,8 @@ func (r *ShoppingListRepository) CreateShoppingListEntity(ctx context.Context, n
 	return &list, nil
 }
 
-func (r *ShoppingListRepository) GetShoppingLists(ctx context.Context) ([]*ShoppingListEntity, error) {
-	rows, err := r.db.Query(ctx, getShoppingListsQuery)
+func (r *ShoppingListRepository) GetShoppingLists(ctx context.Context, ownerUserID *int64) ([]*ShoppingListEntity, error) {
+	rows, err := r.db.Query(ctx, getShoppingListsQuery, ownerUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get shopping lists: %w", err)
 	}
@@ -151,11 +224,18 @@ func (r *ShoppingListRepository) GetShoppingLists(ctx context.Context) ([]*Shopp
 			itemQuantity        *int32
 			itemCompleted       *bool
 			itemCreatedAt       *time.Time
+
+			// Category fields (nullable, item may be uncategorized)
+			categoryId        *int64
+			categoryName      *string
+			categoryColor     *string
+			categorySortOrder *int32
 		)
 
 		err := rows.Scan(
 			&listId, &listName, &listCreatedAt, &listVersion,
 			&itemId, &itemName, &itemQuantity, &itemCompleted, &itemCreatedAt, &itemVersion,
+			&categoryId, &categoryName, &categoryColor, &categorySortOrder,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -186,6 +266,10 @@ func (r *ShoppingListRepository) GetShoppingLists(ctx context.Context) ([]*Shopp
 				CreatedAt: *itemCreatedAt,
 				Version:   *itemVersion,
 			}
+			if categoryId != nil {
+				item.CategoryID = categoryId
+				item.Category = &CategoryEntity{ID: *categoryId, Name: *categoryName, Color: *categoryColor, SortOrder: *categorySortOrder}
+			}
 			list.ShoppingItems = append(list.ShoppingItems, item)
 		}
 	}
@@ -202,13 +286,15 @@ func (r *ShoppingListRepository) GetShoppingLists(ctx context.Context) ([]*Shopp
 	return lists, nil
 }
 
-func (r *ShoppingListRepository) GetShoppingListEntityByID(ctx context.Context, listID int64) (*ShoppingListEntity, error) {
+func (r *ShoppingListRepository) GetShoppingListEntityByID(ctx context.Context, listID int64, ownerUserID *int64) (*ShoppingListEntity, error) {
 	var list ShoppingListEntity
-	err := r.db.QueryRow(ctx, getShoppingListEntityByIDQuery, listID).Scan(
+	err := r.db.QueryRow(ctx, getShoppingListEntityByIDQuery, listID, ownerUserID).Scan(
 		&list.ID, &list.Name, &list.CreatedAt, &list.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			// A cross-user lookup fails the same way as a missing list, so
+			// callers can't use the response to enumerate other users' IDs.
 			return nil, NotFoundError{ResourceType: "shopping list", ResourceID: listID}
 		}
 		return nil, fmt.Errorf("failed to get shopping list: %w", err)
@@ -216,49 +302,68 @@ func (r *ShoppingListRepository) GetShoppingListEntityByID(ctx context.Context,
 	return &list, nil
 }
 
-func (r *ShoppingListRepository) DeleteShoppingListEntity(ctx context.Context, listID int64) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer func(tx pgx.Tx, ctx context.Context) {
-		err := tx.Rollback(ctx)
+func (r *ShoppingListRepository) DeleteShoppingListEntity(ctx context.Context, listID int64, ownerUserID *int64) error {
+	return r.withTx(ctx, func(tx pgx.Tx) error {
+		// First delete all items in the list
+		if _, err := tx.Exec(ctx, deleteGroceryItemEntitiesByListQuery, listID); err != nil {
+			return fmt.Errorf("failed to delete grocery items: %w", err)
+		}
+
+		// Then delete the list
+		result, err := tx.Exec(ctx, deleteShoppingListEntityQuery, listID, ownerUserID)
 		if err != nil {
-			log.Printf("failed to rollback transaction: %v", err)
+			return fmt.Errorf("failed to delete shopping list: %w", err)
 		}
-	}(tx, ctx)
 
-	// First delete all items in the list
-	_, err = tx.Exec(ctx, deleteGroceryItemEntitiesByListQuery, listID)
-	if err != nil {
-		return fmt.Errorf("failed to delete grocery items: %w", err)
-	}
+		if result.RowsAffected() == 0 {
+			return NotFoundError{ResourceType: "shopping list", ResourceID: listID}
+		}
 
-	// Then delete the list
-	result, err := tx.Exec(ctx, deleteShoppingListEntityQuery, listID)
+		return nil
+	})
+}
+
+// withTx begins a transaction, runs fn, and commits on nil error or rolls
+// back otherwise. Returning a NotFoundError (or any other error) from fn
+// rolls back instead of silently committing a partial change.
+func (r *ShoppingListRepository) withTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	return withTx(ctx, r.db, fn)
+}
+
+// txBeginner is the subset of *pgxpool.Pool that withTx needs, so tests can
+// substitute a pgxmock pool without a real database.
+type txBeginner interface {
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+func withTx(ctx context.Context, db txBeginner, fn func(pgx.Tx) error) error {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to delete shopping list: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("failed to rollback transaction: %v", err)
+		}
+	}()
 
-	rowsAffected := result.RowsAffected()
-
-	if rowsAffected == 0 {
-		return NotFoundError{ResourceType: "shopping list", ResourceID: listID}
+	if err := fn(tx); err != nil {
+		return err
 	}
 
 	return tx.Commit(ctx)
 }
 
-func (r *ShoppingListRepository) CreateGroceryItemEntity(ctx context.Context, listID int64, name string, quantity int32) (*GroceryItemEntity, error) {
-	// First verify the shopping list exists
-	_, err := r.GetShoppingListEntityByID(ctx, listID)
+func (r *ShoppingListRepository) CreateGroceryItemEntity(ctx context.Context, listID int64, name string, quantity int32, categoryID *int64, ownerUserID *int64) (*GroceryItemEntity, error) {
+	// First verify the shopping list exists and is owned by the caller
+	_, err := r.GetShoppingListEntityByID(ctx, listID, ownerUserID)
 	if err != nil {
 		return nil, err
 	}
 
 	var item GroceryItemEntity
-	err = r.db.QueryRow(ctx, createGroceryItemEntityQuery, listID, name, quantity).Scan(
-		&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version,
+	err = r.db.QueryRow(ctx, createGroceryItemEntityQuery, listID, name, quantity, categoryID).Scan(
+		&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version, &item.CategoryID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create grocery item: %w", err)
@@ -266,10 +371,24 @@ func (r *ShoppingListRepository) CreateGroceryItemEntity(ctx context.Context, li
 	return &item, nil
 }
 
-func (r *ShoppingListRepository) UpdateGroceryItemEntity(ctx context.Context, itemID int64, listID int64, name string, quantity int32, version int64) (*GroceryItemEntity, error) {
+func (r *ShoppingListRepository) GetGroceryItemEntity(ctx context.Context, itemID int64, listID int64) (*GroceryItemEntity, error) {
 	var item GroceryItemEntity
-	err := r.db.QueryRow(ctx, updateGroceryItemEntityQuery, name, quantity, itemID, listID, version).Scan(
-		&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version,
+	err := r.db.QueryRow(ctx, getGroceryItemEntityQuery, itemID, listID).Scan(
+		&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version, &item.CategoryID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NotFoundError{ResourceType: "grocery item", ResourceID: itemID}
+		}
+		return nil, fmt.Errorf("failed to get grocery item: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *ShoppingListRepository) UpdateGroceryItemEntity(ctx context.Context, itemID int64, listID int64, name string, quantity int32, categoryID *int64, version int64, ownerUserID *int64) (*GroceryItemEntity, error) {
+	var item GroceryItemEntity
+	err := r.db.QueryRow(ctx, updateGroceryItemEntityQuery, name, quantity, categoryID, itemID, listID, version, ownerUserID).Scan(
+		&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version, &item.CategoryID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -293,9 +412,9 @@ func (r *ShoppingListRepository) UpdateGroceryItemEntity(ctx context.Context, it
 	return &item, nil
 }
 
-func (r *ShoppingListRepository) ToggleGroceryItemEntity(ctx context.Context, itemID int64, listID int64, version int64) (*GroceryItemEntity, error) {
+func (r *ShoppingListRepository) ToggleGroceryItemEntity(ctx context.Context, itemID int64, listID int64, version int64, ownerUserID *int64) (*GroceryItemEntity, error) {
 	var item GroceryItemEntity
-	err := r.db.QueryRow(ctx, toggleGroceryItemEntityQuery, itemID, listID, version).Scan(
+	err := r.db.QueryRow(ctx, toggleGroceryItemEntityQuery, itemID, listID, version, ownerUserID).Scan(
 		&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version,
 	)
 	if err != nil {
@@ -319,3 +438,281 @@ func (r *ShoppingListRepository) ToggleGroceryItemEntity(ctx context.Context, it
 	}
 	return &item, nil
 }
+
+// bulkOpOutcome is the immediate result of running one BulkItemOp's
+// statement, before it's been resolved into a BulkItemOpResult. noRows
+// means the statement matched nothing, which is ambiguous between a
+// missing item and a stale version until resolveBulkOpOutcome checks.
+type bulkOpOutcome struct {
+	item    *GroceryItemEntity
+	deleted bool
+	noRows  bool
+	err     error
+}
+
+func (r *ShoppingListRepository) queueBulkOpStatement(batch *pgx.Batch, listID int64, op BulkItemOp, ownerUserID *int64) {
+	switch op.Op {
+	case BulkItemOpAdd:
+		batch.Queue(createGroceryItemEntityQuery, listID, op.Name, op.Quantity, op.CategoryID)
+	case BulkItemOpUpdate:
+		batch.Queue(updateGroceryItemEntityQuery, op.Name, op.Quantity, op.CategoryID, op.ItemID, listID, op.Version, ownerUserID)
+	case BulkItemOpToggle:
+		batch.Queue(toggleGroceryItemEntityQuery, op.ItemID, listID, op.Version, ownerUserID)
+	case BulkItemOpDelete:
+		batch.Queue(bulkDeleteGroceryItemEntityQuery, op.ItemID, listID, op.Version, ownerUserID)
+	}
+}
+
+func readBulkOpOutcome(br pgx.BatchResults, op BulkItemOp) bulkOpOutcome {
+	switch op.Op {
+	case BulkItemOpAdd:
+		var item GroceryItemEntity
+		err := br.QueryRow().Scan(&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version, &item.CategoryID)
+		if err != nil {
+			return bulkOpOutcome{err: fmt.Errorf("failed to add item %q: %w", op.Name, err)}
+		}
+		return bulkOpOutcome{item: &item}
+
+	case BulkItemOpUpdate:
+		var item GroceryItemEntity
+		err := br.QueryRow().Scan(&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version, &item.CategoryID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return bulkOpOutcome{noRows: true}
+		}
+		if err != nil {
+			return bulkOpOutcome{err: fmt.Errorf("failed to update item %d: %w", op.ItemID, err)}
+		}
+		return bulkOpOutcome{item: &item}
+
+	case BulkItemOpToggle:
+		var item GroceryItemEntity
+		err := br.QueryRow().Scan(&item.ID, &item.ListID, &item.Name, &item.Quantity, &item.Completed, &item.CreatedAt, &item.Version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return bulkOpOutcome{noRows: true}
+		}
+		if err != nil {
+			return bulkOpOutcome{err: fmt.Errorf("failed to toggle item %d: %w", op.ItemID, err)}
+		}
+		return bulkOpOutcome{item: &item}
+
+	case BulkItemOpDelete:
+		tag, err := br.Exec()
+		if err != nil {
+			return bulkOpOutcome{err: fmt.Errorf("failed to delete item %d: %w", op.ItemID, err)}
+		}
+		if tag.RowsAffected() == 0 {
+			return bulkOpOutcome{noRows: true}
+		}
+		return bulkOpOutcome{deleted: true}
+
+	default:
+		return bulkOpOutcome{err: fmt.Errorf("unknown bulk op %q", op.Op)}
+	}
+}
+
+// resolveBulkOpOutcome turns a bulkOpOutcome into a BulkItemOpResult. For
+// Update/Toggle/Delete, noRows is ambiguous between "item doesn't exist"
+// and "stale version", the same ambiguity UpdateGroceryItemEntity and
+// ToggleGroceryItemEntity resolve by re-reading the current version.
+func (r *ShoppingListRepository) resolveBulkOpOutcome(ctx context.Context, tx pgx.Tx, listID int64, op BulkItemOp, outcome bulkOpOutcome) BulkItemOpResult {
+	if outcome.err != nil {
+		return BulkItemOpResult{ItemID: op.ItemID, Err: outcome.err}
+	}
+
+	if outcome.noRows {
+		var currentVersion int64
+		err := tx.QueryRow(ctx, getCurrentGroceryItemEntityVersionQuery, op.ItemID, listID).Scan(&currentVersion)
+		if errors.Is(err, sql.ErrNoRows) {
+			return BulkItemOpResult{ItemID: op.ItemID, Err: NotFoundError{ResourceType: "grocery item", ResourceID: op.ItemID}}
+		}
+		if err != nil {
+			return BulkItemOpResult{ItemID: op.ItemID, Err: fmt.Errorf("failed to check version for item %d: %w", op.ItemID, err)}
+		}
+		lockErr := OptimisticLockError{
+			ResourceType:    "grocery item",
+			ResourceID:      op.ItemID,
+			CurrentVersion:  currentVersion,
+			ProvidedVersion: op.Version,
+		}
+		return BulkItemOpResult{ItemID: op.ItemID, LockErr: &lockErr}
+	}
+
+	if outcome.deleted {
+		return BulkItemOpResult{ItemID: op.ItemID, Success: true}
+	}
+	return BulkItemOpResult{ItemID: outcome.item.ID, Success: true, Item: outcome.item}
+}
+
+// applyBulkOpWithSavepoint runs a single op inside its own savepoint, so in
+// best-effort mode a failing op can be rolled back without discarding the
+// ops that already succeeded earlier in the same transaction.
+func (r *ShoppingListRepository) applyBulkOpWithSavepoint(ctx context.Context, tx pgx.Tx, index int, listID int64, op BulkItemOp, ownerUserID *int64) BulkItemOpResult {
+	savepoint := fmt.Sprintf("bulk_op_%d", index)
+	if _, err := tx.Exec(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return BulkItemOpResult{ItemID: op.ItemID, Err: fmt.Errorf("failed to create savepoint: %w", err)}
+	}
+
+	batch := &pgx.Batch{}
+	r.queueBulkOpStatement(batch, listID, op, ownerUserID)
+	br := tx.SendBatch(ctx, batch)
+	outcome := readBulkOpOutcome(br, op)
+	if err := br.Close(); err != nil && outcome.err == nil {
+		outcome.err = fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	result := r.resolveBulkOpOutcome(ctx, tx, listID, op, outcome)
+
+	finalize := "RELEASE SAVEPOINT " + savepoint
+	if !result.Success {
+		finalize = "ROLLBACK TO SAVEPOINT " + savepoint
+	}
+	if _, err := tx.Exec(ctx, finalize); err != nil {
+		log.Printf("failed to finalize savepoint %s: %v", savepoint, err)
+	}
+
+	return result
+}
+
+// BulkUpdateItems applies a batch of add/update/toggle/delete operations
+// against a list's items in a single transaction.
+//
+// In the default (all-or-nothing) mode the whole batch runs as one pgx.Batch,
+// pipelining every op's statement into a single round trip, and the
+// transaction only commits if every op succeeded. Best-effort mode trades
+// that pipelining away: each op gets its own savepoint and round trip so
+// ops that succeed are kept even if a later one fails.
+func (r *ShoppingListRepository) BulkUpdateItems(ctx context.Context, listID int64, ops []BulkItemOp, bestEffort bool, ownerUserID *int64) ([]BulkItemOpResult, error) {
+	if _, err := r.GetShoppingListEntityByID(ctx, listID, ownerUserID); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkItemOpResult, len(ops))
+
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		if bestEffort {
+			for i, op := range ops {
+				results[i] = r.applyBulkOpWithSavepoint(ctx, tx, i, listID, op, ownerUserID)
+			}
+			return nil
+		}
+
+		const batchSavepoint = "bulk_all"
+		if _, err := tx.Exec(ctx, "SAVEPOINT "+batchSavepoint); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		batch := &pgx.Batch{}
+		for _, op := range ops {
+			r.queueBulkOpStatement(batch, listID, op, ownerUserID)
+		}
+
+		br := tx.SendBatch(ctx, batch)
+		outcomes := make([]bulkOpOutcome, len(ops))
+		statementErr := false
+		for i, op := range ops {
+			outcomes[i] = readBulkOpOutcome(br, op)
+			if outcomes[i].err != nil {
+				statementErr = true
+			}
+		}
+		if err := br.Close(); err != nil {
+			statementErr = true
+		}
+
+		if statementErr {
+			// A real Postgres error (e.g. a bad category FK) partway through
+			// the batch aborts the transaction for every op queued after it,
+			// so their outcomes don't reflect what they'd have done on their
+			// own. Undo the whole batch and re-run each op in its own
+			// savepoint to get an accurate per-op result; the transaction
+			// still rolls back overall if any op fails on its own.
+			if _, err := tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+batchSavepoint); err != nil {
+				return fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			for i, op := range ops {
+				results[i] = r.applyBulkOpWithSavepoint(ctx, tx, i, listID, op, ownerUserID)
+			}
+		} else {
+			for i, op := range ops {
+				results[i] = r.resolveBulkOpOutcome(ctx, tx, listID, op, outcomes[i])
+			}
+		}
+
+		for _, result := range results {
+			if !result.Success {
+				return errBulkOpFailed
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBulkOpFailed) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ShoppingListCategorySummary reports the item counts for a single category
+// (or for uncategorized items, when Category is nil) within a list.
+type ShoppingListCategorySummary struct {
+	Category  *CategoryEntity `json:"category,omitempty"`
+	Total     int64           `json:"total"`
+	Completed int64           `json:"completed"`
+	Remaining int64           `json:"remaining"`
+}
+
+type ShoppingListSummary struct {
+	Categories []ShoppingListCategorySummary `json:"categories"`
+	Total      int64                         `json:"total"`
+	Completed  int64                         `json:"completed"`
+	Remaining  int64                         `json:"remaining"`
+}
+
+func (r *ShoppingListRepository) GetShoppingListSummary(ctx context.Context, listID int64, ownerUserID *int64) (*ShoppingListSummary, error) {
+	rows, err := r.db.Query(ctx, getShoppingListSummaryQuery, listID, ownerUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shopping list summary: %w", err)
+	}
+	defer rows.Close()
+
+	summary := &ShoppingListSummary{Categories: []ShoppingListCategorySummary{}}
+
+	for rows.Next() {
+		var (
+			categoryId                  *int64
+			categoryName                *string
+			categoryColor               *string
+			categorySortOrder           *int32
+			total, completed, remaining int64
+		)
+
+		if err := rows.Scan(&categoryId, &categoryName, &categoryColor, &categorySortOrder, &total, &completed, &remaining); err != nil {
+			return nil, fmt.Errorf("failed to scan summary row: %w", err)
+		}
+
+		group := ShoppingListCategorySummary{Total: total, Completed: completed, Remaining: remaining}
+		if categoryId != nil {
+			group.Category = &CategoryEntity{ID: *categoryId, Name: *categoryName, Color: *categoryColor, SortOrder: *categorySortOrder}
+		}
+		summary.Categories = append(summary.Categories, group)
+
+		summary.Total += total
+		summary.Completed += completed
+		summary.Remaining += remaining
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shopping list summary: %w", err)
+	}
+
+	if len(summary.Categories) == 0 {
+		// Either the list has no items, or it doesn't exist/isn't owned by
+		// the caller; GetShoppingListEntityByID gives the right error for
+		// the latter two cases.
+		if _, err := r.GetShoppingListEntityByID(ctx, listID, ownerUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
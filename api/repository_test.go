@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func newTestShoppingListRepository(mock pgxmock.PgxPoolIface) *ShoppingListRepository {
+	return &ShoppingListRepository{db: mock}
+}
+
+func expectShoppingListOwnershipCheck(mock pgxmock.PgxPoolIface, listID int64) {
+	mock.ExpectQuery(`SELECT id, name, created_at, version`).
+		WithArgs(listID, pgxmock.AnyArg()).
+		WillReturnRows(mock.NewRows([]string{"id", "name", "created_at", "version"}).
+			AddRow(listID, "Weekly staples", time.Unix(0, 0), int64(1)))
+}
+
+func expectToggleSuccess(mock pgxmock.PgxPoolIface, itemID int64, version int64) {
+	mock.ExpectQuery(`UPDATE grocery_items`).
+		WithArgs(itemID, pgxmock.AnyArg(), version, pgxmock.AnyArg()).
+		WillReturnRows(mock.NewRows([]string{"id", "list_id", "name", "quantity", "completed", "created_at", "version"}).
+			AddRow(itemID, int64(1), "Milk", int32(2), true, time.Unix(0, 0), version+1))
+}
+
+func expectToggleStaleVersion(mock pgxmock.PgxPoolIface, itemID int64, version int64, currentVersion int64) {
+	mock.ExpectQuery(`UPDATE grocery_items`).
+		WithArgs(itemID, pgxmock.AnyArg(), version, pgxmock.AnyArg()).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT version`).
+		WithArgs(itemID, pgxmock.AnyArg()).
+		WillReturnRows(mock.NewRows([]string{"version"}).AddRow(currentVersion))
+}
+
+func TestWithTx_CommitsOnSuccess(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = withTx(context.Background(), mock, func(tx pgx.Tx) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withTx: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestWithTx_RollsBackWhenFnErrors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	fnErr := errors.New("boom")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = withTx(context.Background(), mock, func(tx pgx.Tx) error {
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Fatalf("expected withTx to return fn's error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestWithTx_RollsBackWhenCommitFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	commitErr := errors.New("commit failed")
+
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(commitErr)
+	mock.ExpectRollback()
+
+	err = withTx(context.Background(), mock, func(tx pgx.Tx) error {
+		return nil
+	})
+	if !errors.Is(err, commitErr) {
+		t.Fatalf("expected withTx to return the commit error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestWithTx_ReturnsErrorWhenBeginFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	beginErr := errors.New("connection refused")
+	mock.ExpectBegin().WillReturnError(beginErr)
+
+	err = withTx(context.Background(), mock, func(tx pgx.Tx) error {
+		t.Fatal("fn should not run when BeginTx fails")
+		return nil
+	})
+	if !errors.Is(err, beginErr) {
+		t.Fatalf("expected withTx to return the begin error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBulkUpdateItems_AllOrNothingRollsBackWhenOneOpFails(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	const listID = int64(1)
+	ops := []BulkItemOp{
+		{Op: BulkItemOpToggle, ItemID: 10, Version: 3},
+		{Op: BulkItemOpToggle, ItemID: 11, Version: 3},
+	}
+
+	expectShoppingListOwnershipCheck(mock, listID)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT bulk_all`).WillReturnResult(pgxmock.NewResult("SAVEPOINT", 0))
+	expectToggleSuccess(mock, 10, 3)
+	expectToggleStaleVersion(mock, 11, 3, 5)
+	mock.ExpectRollback()
+
+	repo := newTestShoppingListRepository(mock)
+	results, err := repo.BulkUpdateItems(context.Background(), listID, ops, false, nil)
+	if err != nil {
+		t.Fatalf("BulkUpdateItems: %v", err)
+	}
+
+	if !results[0].Success {
+		t.Fatalf("expected op 0 to succeed, got %+v", results[0])
+	}
+	if results[1].Success || results[1].LockErr == nil {
+		t.Fatalf("expected op 1 to fail with a lock conflict, got %+v", results[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBulkUpdateItems_BestEffortKeepsSucceededOpsAndCommits(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	const listID = int64(1)
+	ops := []BulkItemOp{
+		{Op: BulkItemOpToggle, ItemID: 10, Version: 3},
+		{Op: BulkItemOpToggle, ItemID: 11, Version: 3},
+	}
+
+	expectShoppingListOwnershipCheck(mock, listID)
+	mock.ExpectBegin()
+
+	mock.ExpectExec(`SAVEPOINT bulk_op_0`).WillReturnResult(pgxmock.NewResult("SAVEPOINT", 0))
+	expectToggleSuccess(mock, 10, 3)
+	mock.ExpectExec(`RELEASE SAVEPOINT bulk_op_0`).WillReturnResult(pgxmock.NewResult("RELEASE", 0))
+
+	mock.ExpectExec(`SAVEPOINT bulk_op_1`).WillReturnResult(pgxmock.NewResult("SAVEPOINT", 0))
+	expectToggleStaleVersion(mock, 11, 3, 5)
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT bulk_op_1`).WillReturnResult(pgxmock.NewResult("ROLLBACK", 0))
+
+	mock.ExpectCommit()
+
+	repo := newTestShoppingListRepository(mock)
+	results, err := repo.BulkUpdateItems(context.Background(), listID, ops, true, nil)
+	if err != nil {
+		t.Fatalf("BulkUpdateItems: %v", err)
+	}
+
+	if !results[0].Success {
+		t.Fatalf("expected op 0 to succeed, got %+v", results[0])
+	}
+	if results[1].Success || results[1].LockErr == nil {
+		t.Fatalf("expected op 1 to fail with a lock conflict, got %+v", results[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestBulkUpdateItems_StatementErrorRetriesEachOpIndividually(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("new mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	const listID = int64(1)
+	ops := []BulkItemOp{
+		{Op: BulkItemOpToggle, ItemID: 10, Version: 3},
+	}
+
+	expectShoppingListOwnershipCheck(mock, listID)
+	mock.ExpectBegin()
+
+	mock.ExpectExec(`SAVEPOINT bulk_all`).WillReturnResult(pgxmock.NewResult("SAVEPOINT", 0))
+	mock.ExpectQuery(`UPDATE grocery_items`).
+		WithArgs(int64(10), pgxmock.AnyArg(), int64(3), pgxmock.AnyArg()).
+		WillReturnError(errors.New("connection reset by peer"))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT bulk_all`).WillReturnResult(pgxmock.NewResult("ROLLBACK", 0))
+
+	mock.ExpectExec(`SAVEPOINT bulk_op_0`).WillReturnResult(pgxmock.NewResult("SAVEPOINT", 0))
+	expectToggleSuccess(mock, 10, 3)
+	mock.ExpectExec(`RELEASE SAVEPOINT bulk_op_0`).WillReturnResult(pgxmock.NewResult("RELEASE", 0))
+
+	mock.ExpectCommit()
+
+	repo := newTestShoppingListRepository(mock)
+	results, err := repo.BulkUpdateItems(context.Background(), listID, ops, false, nil)
+	if err != nil {
+		t.Fatalf("BulkUpdateItems: %v", err)
+	}
+
+	if !results[0].Success {
+		t.Fatalf("expected the retried op to succeed, got %+v", results[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
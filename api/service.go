@@ -10,20 +10,48 @@ const staleClientStateError = "STALE_CLIENT_STATE"
 const staleClientStateErrorMessage = "Client state is stale"
 
 type ShoppingListService struct {
-	repo ShoppingListEntityRepository
+	repo             ShoppingListEntityRepository
+	userRepo         UserRepository
+	jwtService       JWTService
+	activityRecorder ActivityRecorder
+	categoryRepo     CategoryRepository
+	templateRepo     TemplateRepository
 }
 
-func NewShoppingListService(repo *ShoppingListEntityRepository) ShoppingListService {
+func NewShoppingListService(repo *ShoppingListEntityRepository, userRepo UserRepository, jwtService JWTService, activityRecorder ActivityRecorder, categoryRepo CategoryRepository, templateRepo TemplateRepository) ShoppingListService {
 	return ShoppingListService{
-		repo: *repo,
+		repo:             *repo,
+		userRepo:         userRepo,
+		jwtService:       jwtService,
+		activityRecorder: activityRecorder,
+		categoryRepo:     categoryRepo,
+		templateRepo:     templateRepo,
 	}
 }
 
+// recordActivity must never fail the primary operation, so any error from
+// the recorder is logged and swallowed here.
+func (service ShoppingListService) recordActivity(ctx context.Context, listID int64, action string, payload any) {
+	if err := service.activityRecorder.Record(ctx, ownerUserID(ctx), listID, action, payload); err != nil {
+		log.Printf("failed to record activity for shopping list %d action %s: %v", listID, action, err)
+	}
+}
+
+// ownerUserID returns the authenticated caller's user ID, or nil when the
+// request came in over the service-to-service API-key path.
+func ownerUserID(ctx context.Context) *int64 {
+	userID, ok := UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
+
 var _ StrictServerInterface = (*ShoppingListService)(nil)
 
 // Get all shopping lists
 func (service ShoppingListService) GetShoppingLists(ctx context.Context, request GetShoppingListsRequestObject) (GetShoppingListsResponseObject, error) {
-	shoppingListEntities, err := service.repo.GetShoppingLists(ctx)
+	shoppingListEntities, err := service.repo.GetShoppingLists(ctx, ownerUserID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -39,65 +67,409 @@ func (service ShoppingListService) GetShoppingLists(ctx context.Context, request
 
 // Create a new shopping list
 func (service ShoppingListService) CreateShoppingList(ctx context.Context, request CreateShoppingListRequestObject) (CreateShoppingListResponseObject, error) {
-	entity, err := service.repo.CreateShoppingListEntity(ctx, request.Body.Name)
+	entity, err := service.repo.CreateShoppingListEntity(ctx, request.Body.Name, ownerUserID(ctx))
 	if err != nil {
 		return nil, err
 	}
 
+	service.recordActivity(ctx, entity.ID, ActivityActionListCreated, map[string]any{"name": entity.Name})
+
 	return CreateShoppingList201JSONResponse(*getShoppingListModelFromEntity(entity)), nil
 }
 
 // Delete an existing shopping list
 func (service ShoppingListService) DeleteShoppingList(ctx context.Context, request DeleteShoppingListRequestObject) (DeleteShoppingListResponseObject, error) {
-	err := service.repo.DeleteShoppingListEntity(ctx, request.ListId)
+	err := service.repo.DeleteShoppingListEntity(ctx, request.ListId, ownerUserID(ctx))
 	if err != nil {
 		return nil, err
 	}
 
+	service.recordActivity(ctx, request.ListId, ActivityActionListDeleted, map[string]any{})
+
 	return DeleteShoppingList204Response{}, nil
 }
 
 // Add a grocery item to a shopping list
 func (service ShoppingListService) AddGroceryItem(ctx context.Context, request AddGroceryItemRequestObject) (AddGroceryItemResponseObject, error) {
-	entity, err := service.repo.CreateGroceryItemEntity(ctx, request.ListId, request.Body.Name, request.Body.Quantity)
+	entity, err := service.repo.CreateGroceryItemEntity(ctx, request.ListId, request.Body.Name, request.Body.Quantity, request.Body.CategoryId, ownerUserID(ctx))
 	if err != nil {
 		return nil, err
 	}
 
+	service.recordActivity(ctx, request.ListId, ActivityActionItemAdded, map[string]any{"itemId": entity.ID, "name": entity.Name, "quantity": entity.Quantity})
+
 	return AddGroceryItem201JSONResponse(*getGroceryModelFromEntity(entity)), nil
 }
 
 // Update grocery item belonging to a shopping list
 func (service ShoppingListService) UpdateGroceryItem(ctx context.Context, request UpdateGroceryItemRequestObject) (UpdateGroceryItemResponseObject, error) {
-	entity, err := service.repo.UpdateGroceryItemEntity(ctx, request.ItemId, request.ListId, *request.Body.Name, *request.Body.Quantity, request.Body.Version)
+	before, beforeErr := service.repo.GetGroceryItemEntity(ctx, request.ItemId, request.ListId)
+
+	entity, err := service.repo.UpdateGroceryItemEntity(ctx, request.ItemId, request.ListId, *request.Body.Name, *request.Body.Quantity, request.Body.CategoryId, request.Body.Version, ownerUserID(ctx))
 	if err != nil {
 		var lockErr OptimisticLockError
 		if errors.As(err, &lockErr) {
 			log.Printf("Stale client state with when updating grocery item err: %v", lockErr)
+			service.recordActivity(ctx, request.ListId, ActivityActionItemUpdateConflict, map[string]any{
+				"itemId": request.ItemId, "currentVersion": lockErr.CurrentVersion, "providedVersion": lockErr.ProvidedVersion,
+			})
 			return UpdateGroceryItem409JSONResponse{createConflictResponseFromErr(lockErr)}, nil
 		}
 
 		return nil, err
 	}
 
+	payload := map[string]any{"itemId": entity.ID, "after": getGroceryModelFromEntity(entity)}
+	if beforeErr == nil {
+		payload["before"] = getGroceryModelFromEntity(before)
+	}
+	service.recordActivity(ctx, request.ListId, ActivityActionItemUpdated, payload)
+
 	return UpdateGroceryItem200JSONResponse(*getGroceryModelFromEntity(entity)), nil
 }
 
 // Set the "completed" flag on a shopping list item
 func (service ShoppingListService) ToggleGroceryItem(ctx context.Context, request ToggleGroceryItemRequestObject) (ToggleGroceryItemResponseObject, error) {
-	entity, err := service.repo.ToggleGroceryItemEntity(ctx, request.ItemId, request.ListId, request.Body.Version)
+	entity, err := service.repo.ToggleGroceryItemEntity(ctx, request.ItemId, request.ListId, request.Body.Version, ownerUserID(ctx))
 	if err != nil {
 		var lockErr OptimisticLockError
 		if errors.As(err, &lockErr) {
 			log.Printf("Stale client state detected when updating grocery item toggle toggle: %v", lockErr)
+			service.recordActivity(ctx, request.ListId, ActivityActionItemToggleConflict, map[string]any{
+				"itemId": request.ItemId, "currentVersion": lockErr.CurrentVersion, "providedVersion": lockErr.ProvidedVersion,
+			})
 			return ToggleGroceryItem409JSONResponse{createConflictResponseFromErr(lockErr)}, nil
 		}
 		return nil, err
 	}
 
+	service.recordActivity(ctx, request.ListId, ActivityActionItemToggled, map[string]any{"itemId": entity.ID, "completed": entity.Completed})
+
 	return ToggleGroceryItem200JSONResponse(*getGroceryModelFromEntity(entity)), nil
 }
 
+// Apply a batch of add/update/toggle/delete operations to a list's items in
+// one request. Defaults to all-or-nothing; ?mode=best-effort keeps whatever
+// ops succeeded and reports the rest as failed instead of rolling everything
+// back.
+func (service ShoppingListService) BulkUpdateItems(ctx context.Context, request BulkUpdateItemsRequestObject) (BulkUpdateItemsResponseObject, error) {
+	bestEffort := request.Params.Mode != nil && *request.Params.Mode == "best-effort"
+
+	ops := make([]BulkItemOp, 0, len(*request.Body))
+	for _, op := range *request.Body {
+		ops = append(ops, bulkItemOpFromModel(op))
+	}
+
+	results, err := service.repo.BulkUpdateItems(ctx, request.ListId, ops, bestEffort, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	response := BulkUpdateItems200JSONResponse{Results: make([]BulkItemOperationResult, 0, len(results))}
+	succeeded := 0
+	for _, result := range results {
+		response.Results = append(response.Results, bulkItemResultModelFromEntity(result))
+		if result.Success {
+			succeeded++
+		}
+	}
+
+	if succeeded > 0 {
+		service.recordActivity(ctx, request.ListId, ActivityActionItemsBulkUpdated, map[string]any{
+			"opCount": len(ops), "succeeded": succeeded, "failed": len(ops) - succeeded, "bestEffort": bestEffort,
+		})
+	}
+
+	return response, nil
+}
+
+func bulkItemOpFromModel(op BulkItemOperation) BulkItemOp {
+	entity := BulkItemOp{Op: BulkItemOpKind(op.Op), CategoryID: op.CategoryId}
+	if op.ItemId != nil {
+		entity.ItemID = *op.ItemId
+	}
+	if op.Name != nil {
+		entity.Name = *op.Name
+	}
+	if op.Quantity != nil {
+		entity.Quantity = *op.Quantity
+	}
+	if op.Version != nil {
+		entity.Version = *op.Version
+	}
+	return entity
+}
+
+func bulkItemResultModelFromEntity(result BulkItemOpResult) BulkItemOperationResult {
+	return BulkItemOperationResult{
+		ItemId:  result.ItemID,
+		Success: result.Success,
+		Item:    getGroceryModelFromEntity(result.Item),
+		Error:   bulkItemErrorModelFromEntity(result),
+	}
+}
+
+func bulkItemErrorModelFromEntity(result BulkItemOpResult) *BulkItemOperationError {
+	switch {
+	case result.LockErr != nil:
+		currentVersion := result.LockErr.CurrentVersion
+		return &BulkItemOperationError{Error: staleClientStateError, Message: staleClientStateErrorMessage, CurrentVersion: &currentVersion}
+	case result.Err != nil:
+		var notFoundErr NotFoundError
+		if errors.As(result.Err, &notFoundErr) {
+			return &BulkItemOperationError{Error: "ITEM_NOT_FOUND", Message: notFoundErr.Error()}
+		}
+		// Anything else is an unexpected failure (bad FK, driver error, ...);
+		// log the detail server-side rather than putting it in a 200 body.
+		log.Printf("bulk op failed for item %d: %v", result.ItemID, result.Err)
+		return &BulkItemOperationError{Error: "OPERATION_FAILED", Message: "the operation could not be completed"}
+	default:
+		return nil
+	}
+}
+
+// List the activity recorded for a shopping list, newest first
+func (service ShoppingListService) GetShoppingListActivity(ctx context.Context, request GetShoppingListActivityRequestObject) (GetShoppingListActivityResponseObject, error) {
+	if _, err := service.repo.GetShoppingListEntityByID(ctx, request.ListId, ownerUserID(ctx)); err != nil {
+		return nil, err
+	}
+
+	var encodedCursor string
+	if request.Params.Cursor != nil {
+		encodedCursor = *request.Params.Cursor
+	}
+	cursor, err := decodeActivityCursor(encodedCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 50
+	if request.Params.Limit != nil {
+		limit = int(*request.Params.Limit)
+	}
+
+	entries, nextCursor, err := service.activityRecorder.GetActivity(ctx, request.ListId, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := GetShoppingListActivity200JSONResponse{Activity: make([]ActivityEvent, 0, len(entries))}
+	for _, entry := range entries {
+		response.Activity = append(response.Activity, activityModelFromEntity(entry))
+	}
+	if nextCursor != nil {
+		encoded := nextCursor.encode()
+		response.NextCursor = &encoded
+	}
+
+	return response, nil
+}
+
+func activityModelFromEntity(entity *ActivityEntity) ActivityEvent {
+	return ActivityEvent{
+		Id:        entity.ID,
+		UserId:    entity.UserID,
+		Action:    entity.Action,
+		Payload:   entity.Payload,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+// List all categories
+func (service ShoppingListService) GetCategories(ctx context.Context, request GetCategoriesRequestObject) (GetCategoriesResponseObject, error) {
+	entities, err := service.categoryRepo.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories GetCategories200JSONResponse = make([]Category, 0, len(entities))
+	for _, entity := range entities {
+		categories = append(categories, *getCategoryModelFromEntity(entity))
+	}
+
+	return categories, nil
+}
+
+// Create a new category
+func (service ShoppingListService) CreateCategory(ctx context.Context, request CreateCategoryRequestObject) (CreateCategoryResponseObject, error) {
+	entity, err := service.categoryRepo.CreateCategory(ctx, request.Body.Name, request.Body.Color, request.Body.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateCategory201JSONResponse(*getCategoryModelFromEntity(entity)), nil
+}
+
+// Update an existing category
+func (service ShoppingListService) UpdateCategory(ctx context.Context, request UpdateCategoryRequestObject) (UpdateCategoryResponseObject, error) {
+	entity, err := service.categoryRepo.UpdateCategory(ctx, request.CategoryId, request.Body.Name, request.Body.Color, request.Body.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateCategory200JSONResponse(*getCategoryModelFromEntity(entity)), nil
+}
+
+// Delete an existing category
+func (service ShoppingListService) DeleteCategory(ctx context.Context, request DeleteCategoryRequestObject) (DeleteCategoryResponseObject, error) {
+	if err := service.categoryRepo.DeleteCategory(ctx, request.CategoryId); err != nil {
+		return nil, err
+	}
+
+	return DeleteCategory204Response{}, nil
+}
+
+// Get items grouped by category, with per-category and overall totals
+func (service ShoppingListService) GetShoppingListSummary(ctx context.Context, request GetShoppingListSummaryRequestObject) (GetShoppingListSummaryResponseObject, error) {
+	summary, err := service.repo.GetShoppingListSummary(ctx, request.ListId, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return GetShoppingListSummary200JSONResponse(*getSummaryModelFromEntity(summary)), nil
+}
+
+func getSummaryModelFromEntity(entity *ShoppingListSummary) *ShoppingListSummaryModel {
+	groups := make([]ShoppingListCategorySummaryModel, 0, len(entity.Categories))
+	for _, group := range entity.Categories {
+		groups = append(groups, ShoppingListCategorySummaryModel{
+			Category:  getCategoryModelFromEntity(group.Category),
+			Total:     group.Total,
+			Completed: group.Completed,
+			Remaining: group.Remaining,
+		})
+	}
+
+	return &ShoppingListSummaryModel{
+		Categories: groups,
+		Total:      entity.Total,
+		Completed:  entity.Completed,
+		Remaining:  entity.Remaining,
+	}
+}
+
+// Save the current items of a shopping list as a reusable template
+func (service ShoppingListService) CreateTemplate(ctx context.Context, request CreateTemplateRequestObject) (CreateTemplateResponseObject, error) {
+	template, err := service.templateRepo.CreateTemplateFromList(ctx, request.Body.SourceListId, request.Body.Name, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateTemplate201JSONResponse(*getTemplateModelFromEntity(template)), nil
+}
+
+// List the caller's saved templates
+func (service ShoppingListService) GetTemplates(ctx context.Context, request GetTemplatesRequestObject) (GetTemplatesResponseObject, error) {
+	templates, err := service.templateRepo.GetTemplates(ctx, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var response GetTemplates200JSONResponse = make([]ListTemplate, 0, len(templates))
+	for _, template := range templates {
+		response = append(response, *getTemplateModelFromEntity(template))
+	}
+
+	return response, nil
+}
+
+// Spawn a new shopping list from a saved template, atomically
+func (service ShoppingListService) CreateShoppingListFromTemplate(ctx context.Context, request CreateShoppingListFromTemplateRequestObject) (CreateShoppingListFromTemplateResponseObject, error) {
+	list, err := service.templateRepo.SpawnListFromTemplate(ctx, request.TemplateId, request.Body.Name, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	service.recordActivity(ctx, list.ID, ActivityActionListCreated, map[string]any{"name": list.Name, "fromTemplateId": request.TemplateId})
+
+	return CreateShoppingListFromTemplate201JSONResponse(*getShoppingListModelFromEntity(list)), nil
+}
+
+// Import a template shared as JSON by another user
+func (service ShoppingListService) ImportTemplate(ctx context.Context, request ImportTemplateRequestObject) (ImportTemplateResponseObject, error) {
+	export := TemplateExport{Name: request.Body.Name}
+	for _, item := range request.Body.Items {
+		export.Items = append(export.Items, TemplateExportItem{Name: item.Name, Quantity: item.Quantity})
+	}
+
+	template, err := service.templateRepo.ImportTemplate(ctx, export, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ImportTemplate201JSONResponse(*getTemplateModelFromEntity(template)), nil
+}
+
+// Export a template as JSON so it can be shared out-of-band
+func (service ShoppingListService) ExportTemplate(ctx context.Context, request ExportTemplateRequestObject) (ExportTemplateResponseObject, error) {
+	export, err := service.templateRepo.ExportTemplate(ctx, request.TemplateId, ownerUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return ExportTemplate200JSONResponse(*export), nil
+}
+
+func getTemplateModelFromEntity(entity *ListTemplateEntity) *ListTemplate {
+	items := make([]ListTemplateItem, 0, len(entity.Items))
+	for _, item := range entity.Items {
+		items = append(items, ListTemplateItem{Name: item.Name, Quantity: item.Quantity, CategoryId: item.CategoryID})
+	}
+
+	return &ListTemplate{
+		Id:        entity.ID,
+		Name:      entity.Name,
+		CreatedAt: entity.CreatedAt,
+		Items:     items,
+	}
+}
+
+// Register a new user account
+func (service ShoppingListService) RegisterUser(ctx context.Context, request RegisterUserRequestObject) (RegisterUserResponseObject, error) {
+	passwordHash, err := hashPassword(request.Body.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := service.userRepo.CreateUser(ctx, request.Body.Email, passwordHash)
+	if err != nil {
+		if errors.Is(err, ErrEmailAlreadyRegistered) {
+			return RegisterUser409JSONResponse{Error: "EMAIL_ALREADY_REGISTERED", Message: err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	token, err := service.jwtService.IssueToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return RegisterUser201JSONResponse{Token: token}, nil
+}
+
+// Log in with an existing user account
+func (service ShoppingListService) LoginUser(ctx context.Context, request LoginUserRequestObject) (LoginUserResponseObject, error) {
+	user, err := service.userRepo.GetUserByEmail(ctx, request.Body.Email)
+	if err != nil {
+		var notFoundErr NotFoundError
+		if errors.As(err, &notFoundErr) {
+			return LoginUser401JSONResponse{Error: "INVALID_CREDENTIALS", Message: "invalid email or password"}, nil
+		}
+		return nil, err
+	}
+
+	if !passwordMatches(user.PasswordHash, request.Body.Password) {
+		return LoginUser401JSONResponse{Error: "INVALID_CREDENTIALS", Message: "invalid email or password"}, nil
+	}
+
+	token, err := service.jwtService.IssueToken(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoginUser200JSONResponse{Token: token}, nil
+}
+
 func getShoppingListModelFromEntity(entity *ShoppingListEntity) *ShoppingList {
 	if entity == nil {
 		return nil
@@ -133,6 +505,20 @@ func getGroceryModelFromEntity(entity *GroceryItemEntity) *GroceryItem {
 		Completed: entity.Completed,
 		Quantity:  entity.Quantity,
 		Version:   entity.Version,
+		Category:  getCategoryModelFromEntity(entity.Category),
+	}
+}
+
+func getCategoryModelFromEntity(entity *CategoryEntity) *Category {
+	if entity == nil {
+		return nil
+	}
+
+	return &Category{
+		Id:        entity.ID,
+		Name:      entity.Name,
+		Color:     entity.Color,
+		SortOrder: entity.SortOrder,
 	}
 }
 
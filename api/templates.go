@@ -0,0 +1,290 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ListTemplateItemEntity struct {
+	ID         int64  `json:"id" db:"id"`
+	TemplateID int64  `json:"templateId" db:"template_id"`
+	Name       string `json:"name" db:"name"`
+	Quantity   int32  `json:"quantity" db:"quantity"`
+	CategoryID *int64 `json:"categoryId,omitempty" db:"category_id"`
+}
+
+type ListTemplateEntity struct {
+	ID          int64                    `json:"id" db:"id"`
+	Name        string                   `json:"name" db:"name"`
+	OwnerUserID *int64                   `json:"-" db:"owner_user_id"`
+	CreatedAt   time.Time                `json:"createdAt" db:"created_at"`
+	Items       []ListTemplateItemEntity `json:"items"`
+}
+
+// TemplateExport is the JSON representation used to share a template
+// out-of-band, independent of the database IDs it was created with.
+type TemplateExport struct {
+	Name  string               `json:"name"`
+	Items []TemplateExportItem `json:"items"`
+}
+
+type TemplateExportItem struct {
+	Name     string `json:"name"`
+	Quantity int32  `json:"quantity"`
+}
+
+type TemplateRepository interface {
+	CreateTemplateFromList(ctx context.Context, listID int64, name string, ownerUserID *int64) (*ListTemplateEntity, error)
+	GetTemplates(ctx context.Context, ownerUserID *int64) ([]*ListTemplateEntity, error)
+	ImportTemplate(ctx context.Context, export TemplateExport, ownerUserID *int64) (*ListTemplateEntity, error)
+	ExportTemplate(ctx context.Context, templateID int64, ownerUserID *int64) (*TemplateExport, error)
+	SpawnListFromTemplate(ctx context.Context, templateID int64, listName string, ownerUserID *int64) (*ShoppingListEntity, error)
+}
+
+type PostgresTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTemplateRepository(db *pgxpool.Pool) *PostgresTemplateRepository {
+	return &PostgresTemplateRepository{db: db}
+}
+
+const (
+	createListTemplateQuery = `
+		INSERT INTO list_templates (name, owner_user_id, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, name, owner_user_id, created_at`
+
+	copyListItemsIntoTemplateQuery = `
+		INSERT INTO list_template_items (template_id, name, quantity, category_id)
+		SELECT $1, name, quantity, category_id FROM grocery_items WHERE list_id = $2`
+
+	getShoppingListOwnerCheckQuery = `
+		SELECT id
+		FROM shopping_lists
+		WHERE id = $1 AND ($2::bigint IS NULL OR owner_user_id = $2)`
+
+	insertListTemplateItemQuery = `
+		INSERT INTO list_template_items (template_id, name, quantity, category_id)
+		VALUES ($1, $2, $3, $4)`
+
+	getListTemplatesQuery = `
+		SELECT id, name, owner_user_id, created_at
+		FROM list_templates
+		WHERE $1::bigint IS NULL OR owner_user_id = $1
+		ORDER BY created_at DESC`
+
+	getListTemplateByIDQuery = `
+		SELECT id, name, owner_user_id, created_at
+		FROM list_templates
+		WHERE id = $1 AND ($2::bigint IS NULL OR owner_user_id = $2)`
+
+	getListTemplateItemsQuery = `
+		SELECT id, template_id, name, quantity, category_id
+		FROM list_template_items
+		WHERE template_id = $1
+		ORDER BY id`
+)
+
+func (r *PostgresTemplateRepository) CreateTemplateFromList(ctx context.Context, listID int64, name string, ownerUserID *int64) (*ListTemplateEntity, error) {
+	var template ListTemplateEntity
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var sourceListID int64
+		err := tx.QueryRow(ctx, getShoppingListOwnerCheckQuery, listID, ownerUserID).Scan(&sourceListID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				// A cross-user source list fails the same way as a missing
+				// one, so callers can't use this to exfiltrate another
+				// user's items into a template they own.
+				return NotFoundError{ResourceType: "shopping list", ResourceID: listID}
+			}
+			return fmt.Errorf("failed to verify shopping list ownership: %w", err)
+		}
+
+		err = tx.QueryRow(ctx, createListTemplateQuery, name, ownerUserID).Scan(
+			&template.ID, &template.Name, &template.OwnerUserID, &template.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create template: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, copyListItemsIntoTemplateQuery, template.ID, listID); err != nil {
+			return fmt.Errorf("failed to copy list items into template: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ExportableTemplate(ctx, template)
+}
+
+// ExportableTemplate loads the items for a template that's already been
+// fetched, so callers don't have to re-query it by ID.
+func (r *PostgresTemplateRepository) ExportableTemplate(ctx context.Context, template ListTemplateEntity) (*ListTemplateEntity, error) {
+	items, err := r.getTemplateItems(ctx, template.ID)
+	if err != nil {
+		return nil, err
+	}
+	template.Items = items
+	return &template, nil
+}
+
+func (r *PostgresTemplateRepository) getTemplateItems(ctx context.Context, templateID int64) ([]ListTemplateItemEntity, error) {
+	rows, err := r.db.Query(ctx, getListTemplateItemsQuery, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []ListTemplateItemEntity{}
+	for rows.Next() {
+		var item ListTemplateItemEntity
+		if err := rows.Scan(&item.ID, &item.TemplateID, &item.Name, &item.Quantity, &item.CategoryID); err != nil {
+			return nil, fmt.Errorf("failed to scan template item row: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating template items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *PostgresTemplateRepository) GetTemplates(ctx context.Context, ownerUserID *int64) ([]*ListTemplateEntity, error) {
+	rows, err := r.db.Query(ctx, getListTemplatesQuery, ownerUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*ListTemplateEntity
+	for rows.Next() {
+		var template ListTemplateEntity
+		if err := rows.Scan(&template.ID, &template.Name, &template.OwnerUserID, &template.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan template row: %w", err)
+		}
+		templates = append(templates, &template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating templates: %w", err)
+	}
+
+	for _, template := range templates {
+		items, err := r.getTemplateItems(ctx, template.ID)
+		if err != nil {
+			return nil, err
+		}
+		template.Items = items
+	}
+
+	return templates, nil
+}
+
+func (r *PostgresTemplateRepository) getTemplateByID(ctx context.Context, templateID int64, ownerUserID *int64) (*ListTemplateEntity, error) {
+	var template ListTemplateEntity
+	err := r.db.QueryRow(ctx, getListTemplateByIDQuery, templateID, ownerUserID).Scan(
+		&template.ID, &template.Name, &template.OwnerUserID, &template.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NotFoundError{ResourceType: "template", ResourceID: templateID}
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return &template, nil
+}
+
+func (r *PostgresTemplateRepository) ImportTemplate(ctx context.Context, export TemplateExport, ownerUserID *int64) (*ListTemplateEntity, error) {
+	var template ListTemplateEntity
+
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, createListTemplateQuery, export.Name, ownerUserID).Scan(
+			&template.ID, &template.Name, &template.OwnerUserID, &template.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create template: %w", err)
+		}
+
+		for _, item := range export.Items {
+			if _, err := tx.Exec(ctx, insertListTemplateItemQuery, template.ID, item.Name, item.Quantity, nil); err != nil {
+				return fmt.Errorf("failed to import template item %q: %w", item.Name, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ExportableTemplate(ctx, template)
+}
+
+func (r *PostgresTemplateRepository) ExportTemplate(ctx context.Context, templateID int64, ownerUserID *int64) (*TemplateExport, error) {
+	template, err := r.getTemplateByID(ctx, templateID, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.getTemplateItems(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &TemplateExport{Name: template.Name, Items: make([]TemplateExportItem, 0, len(items))}
+	for _, item := range items {
+		export.Items = append(export.Items, TemplateExportItem{Name: item.Name, Quantity: item.Quantity})
+	}
+
+	return export, nil
+}
+
+// SpawnListFromTemplate creates a new shopping list and inserts all of the
+// template's items atomically: either the whole list appears, or none of it
+// does.
+func (r *PostgresTemplateRepository) SpawnListFromTemplate(ctx context.Context, templateID int64, listName string, ownerUserID *int64) (*ShoppingListEntity, error) {
+	if _, err := r.getTemplateByID(ctx, templateID, ownerUserID); err != nil {
+		return nil, err
+	}
+	items, err := r.getTemplateItems(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var list ShoppingListEntity
+	err = withTx(ctx, r.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, createShoppingListEntityQuery, listName, ownerUserID).Scan(
+			&list.ID, &list.Name, &list.CreatedAt, &list.Version,
+		); err != nil {
+			return fmt.Errorf("failed to create shopping list from template: %w", err)
+		}
+
+		for _, item := range items {
+			var created GroceryItemEntity
+			if err := tx.QueryRow(ctx, createGroceryItemEntityQuery, list.ID, item.Name, item.Quantity, item.CategoryID).Scan(
+				&created.ID, &created.ListID, &created.Name, &created.Quantity, &created.Completed, &created.CreatedAt, &created.Version, &created.CategoryID,
+			); err != nil {
+				return fmt.Errorf("failed to add template item %q: %w", item.Name, err)
+			}
+			list.ShoppingItems = append(list.ShoppingItems, created)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserEntity struct {
+	ID           int64     `json:"id" db:"id"`
+	Email        string    `json:"email" db:"email"`
+	PasswordHash string    `json:"-" db:"password_hash"`
+	CreatedAt    time.Time `json:"createdAt" db:"created_at"`
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, email string, passwordHash string) (*UserEntity, error)
+	GetUserByEmail(ctx context.Context, email string) (*UserEntity, error)
+}
+
+type PostgresUserRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresUserRepository(db *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+const (
+	createUserQuery = `
+		INSERT INTO users (email, password_hash, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING id, email, password_hash, created_at`
+
+	getUserByEmailQuery = `
+		SELECT id, email, password_hash, created_at
+		FROM users
+		WHERE email = $1`
+)
+
+var ErrEmailAlreadyRegistered = errors.New("email already registered")
+
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, email string, passwordHash string) (*UserEntity, error) {
+	var user UserEntity
+	err := r.db.QueryRow(ctx, createUserQuery, email, passwordHash).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrEmailAlreadyRegistered
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return &user, nil
+}
+
+func (r *PostgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*UserEntity, error) {
+	var user UserEntity
+	err := r.db.QueryRow(ctx, getUserByEmailQuery, email).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NotFoundError{ResourceType: "user", ResourceID: 0}
+		}
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return &user, nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func passwordMatches(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,148 @@
+// Command server runs the shopping-list API outside of Lambda, for local
+// development and for deployments that want a long-lived process exposing
+// REST, gRPC, or both on the same repository.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	api "shoppinglist/api"
+	pb "shoppinglist/pb"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+)
+
+const migrationURL = "file://migration/"
+const jwtTTL = 24 * time.Hour
+
+func main() {
+	databaseURL := os.Getenv("DB_URL")
+	dbPool, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		log.Fatalf("unable to connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	runDbMigrations(migrationURL, databaseURL)
+
+	var repo api.ShoppingListEntityRepository = api.NewShoppingListRepository(dbPool)
+	userRepo := api.NewPostgresUserRepository(dbPool)
+	jwtService, err := newJWTServiceFromEnv()
+	if err != nil {
+		log.Fatalf("jwt service: %v", err)
+	}
+	activityRecorder := api.NewPostgresActivityRecorder(dbPool)
+	categoryRepo := api.NewPostgresCategoryRepository(dbPool)
+	templateRepo := api.NewPostgresTemplateRepository(dbPool)
+	apiKey := os.Getenv("API_KEY")
+
+	transport := os.Getenv("TRANSPORT")
+	if transport == "" {
+		transport = "http"
+	}
+
+	switch transport {
+	case "http":
+		runHTTP(repo, userRepo, jwtService, activityRecorder, categoryRepo, templateRepo, apiKey)
+	case "grpc":
+		runGRPC(repo, jwtService, apiKey)
+	case "both":
+		go runGRPC(repo, jwtService, apiKey)
+		runHTTP(repo, userRepo, jwtService, activityRecorder, categoryRepo, templateRepo, apiKey)
+	default:
+		log.Fatalf("unknown TRANSPORT %q, want http|grpc|both", transport)
+	}
+}
+
+func runHTTP(repo api.ShoppingListEntityRepository, userRepo api.UserRepository, jwtService api.JWTService, activityRecorder api.ActivityRecorder, categoryRepo api.CategoryRepository, templateRepo api.TemplateRepository, apiKey string) {
+	service := api.NewShoppingListService(&repo, userRepo, jwtService, activityRecorder, categoryRepo, templateRepo)
+	strictHandler := api.NewStrictHandler(service, nil)
+	mux := http.NewServeMux()
+	handler := api.HandlerFromMux(strictHandler, mux)
+
+	authService := api.NewAuthService(apiKey, jwtService)
+	addr := ":" + envOr("HTTP_PORT", "8080")
+	log.Printf("http transport listening on %s", addr)
+	if err := http.ListenAndServe(addr, authService.AuthMiddleware(handler)); err != nil {
+		log.Fatalf("http server: %v", err)
+	}
+}
+
+func runGRPC(repo api.ShoppingListEntityRepository, jwtService api.JWTService, apiKey string) {
+	addr := ":" + envOr("GRPC_PORT", "9090")
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("grpc listen: %v", err)
+	}
+
+	authService := api.NewAuthService(apiKey, jwtService)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(api.NewAuthUnaryInterceptor(authService)))
+	pb.RegisterShoppingListServiceServer(grpcServer, api.NewGRPCServer(repo))
+
+	log.Printf("grpc transport listening on %s", addr)
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("grpc server: %v", err)
+	}
+}
+
+// newJWTServiceFromEnv builds the JWTService used to sign and verify
+// bearer tokens. JWT_ALGORITHM selects HS256 (default, HMAC with
+// JWT_SECRET) or RS256 (JWT_RSA_PRIVATE_KEY_PATH/JWT_RSA_PUBLIC_KEY_PATH,
+// both PEM-encoded).
+func newJWTServiceFromEnv() (api.JWTService, error) {
+	if strings.EqualFold(os.Getenv("JWT_ALGORITHM"), "RS256") {
+		privatePEM, err := os.ReadFile(os.Getenv("JWT_RSA_PRIVATE_KEY_PATH"))
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("read RSA private key: %w", err)
+		}
+		privateKey, err := api.ParseRSAPrivateKeyPEM(privatePEM)
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("parse RSA private key: %w", err)
+		}
+
+		publicPEM, err := os.ReadFile(os.Getenv("JWT_RSA_PUBLIC_KEY_PATH"))
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("read RSA public key: %w", err)
+		}
+		publicKey, err := api.ParseRSAPublicKeyPEM(publicPEM)
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("parse RSA public key: %w", err)
+		}
+
+		return api.NewRSAJWTService(privateKey, publicKey, jwtTTL), nil
+	}
+
+	return api.NewHMACJWTService([]byte(os.Getenv("JWT_SECRET")), jwtTTL), nil
+}
+
+func runDbMigrations(migrationURL string, dbSource string) {
+	migration, err := migrate.New(migrationURL, dbSource)
+	if err != nil {
+		log.Fatal("cannot create new migrate instance")
+	}
+
+	if err = migration.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		log.Fatal("failed to run migrate up")
+	}
+
+	log.Printf("db migrated successfully")
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fmt.Sprint(fallback)
+}
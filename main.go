@@ -8,6 +8,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
+
 	api "shoppinglist/api"
 
 	"github.com/aws/aws-lambda-go/lambda"
@@ -17,6 +20,8 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const jwtTTL = 24 * time.Hour
+
 var (
 	apiHandler   http.Handler
 	dbPool       *pgxpool.Pool
@@ -36,6 +41,36 @@ func runDbMigrations(migrationURL string, dbSource string) {
 	log.Printf("Db migrated successfully")
 }
 
+// newJWTServiceFromEnv builds the JWTService used to sign and verify
+// bearer tokens. JWT_ALGORITHM selects HS256 (default, HMAC with
+// JWT_SECRET) or RS256 (JWT_RSA_PRIVATE_KEY_PATH/JWT_RSA_PUBLIC_KEY_PATH,
+// both PEM-encoded).
+func newJWTServiceFromEnv() (api.JWTService, error) {
+	if strings.EqualFold(os.Getenv("JWT_ALGORITHM"), "RS256") {
+		privatePEM, err := os.ReadFile(os.Getenv("JWT_RSA_PRIVATE_KEY_PATH"))
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("read RSA private key: %w", err)
+		}
+		privateKey, err := api.ParseRSAPrivateKeyPEM(privatePEM)
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("parse RSA private key: %w", err)
+		}
+
+		publicPEM, err := os.ReadFile(os.Getenv("JWT_RSA_PUBLIC_KEY_PATH"))
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("read RSA public key: %w", err)
+		}
+		publicKey, err := api.ParseRSAPublicKeyPEM(publicPEM)
+		if err != nil {
+			return api.JWTService{}, fmt.Errorf("parse RSA public key: %w", err)
+		}
+
+		return api.NewRSAJWTService(privateKey, publicKey, jwtTTL), nil
+	}
+
+	return api.NewHMACJWTService([]byte(os.Getenv("JWT_SECRET")), jwtTTL), nil
+}
+
 func init() {
 	databaseUrl := os.Getenv("DB_URL")
 	dbpool, err := pgxpool.New(context.Background(), databaseUrl)
@@ -47,11 +82,22 @@ func init() {
 	runDbMigrations(migrationUrl, databaseUrl)
 
 	var repo api.ShoppingListEntityRepository = api.NewShoppingListRepository(dbpool)
-	service := api.NewShoppingListService(&repo)
+	userRepo := api.NewPostgresUserRepository(dbpool)
+	jwtService, err := newJWTServiceFromEnv()
+	if err != nil {
+		fmt.Printf("jwt service: %v\n", err)
+		os.Exit(1)
+	}
+	activityRecorder := api.NewPostgresActivityRecorder(dbpool)
+	categoryRepo := api.NewPostgresCategoryRepository(dbpool)
+	templateRepo := api.NewPostgresTemplateRepository(dbpool)
+
+	service := api.NewShoppingListService(&repo, userRepo, jwtService, activityRecorder, categoryRepo, templateRepo)
 	strictHandler := api.NewStrictHandler(service, nil)
 	mux := http.NewServeMux()
 
-	apiHandler = api.HandlerFromMux(strictHandler, mux)
+	authService := api.NewAuthService(os.Getenv("API_KEY"), jwtService)
+	apiHandler = authService.AuthMiddleware(api.HandlerFromMux(strictHandler, mux))
 }
 
 func main() {